@@ -36,21 +36,35 @@ type (
 		// the circuit breaker is half-closed (with some probability). Otherwise, return an
 		// ErrCircuitOpen. If the function times out, the circuit breaker will fail with an
 		// ErrInvocationTimeout. If the function is invoked and yields a value before the
-		// timeout elapses, that value is returned.
+		// timeout elapses, that value is returned. If maxConcurrency in-flight invocations
+		// are already running, this call blocks up to maxConcurrencyTimeout for a free slot
+		// before failing with ErrMaxConcurrency. Equivalent to CallContext with
+		// context.Background().
 		Call(f BreakerFunc) error
 
+		// CallContext behaves like Call, but derives the context passed to f from ctx
+		// instead of context.Background(), so that tracing values, request deadlines, and
+		// cancellation propagate into f. If ctx is done before f returns and before the
+		// invocation timeout elapses, the call returns ctx.Err().
+		CallContext(ctx context.Context, f BreakerFunc) error
+
 		// CallAsync invokes the given function in a goroutine, returning a channel which
 		// may receive one non-nil error value and then close. The channel will close without
-		// writing a value on success.
+		// writing a value on success. Equivalent to CallAsyncContext with context.Background().
 		CallAsync(f BreakerFunc) <-chan error
+
+		// CallAsyncContext behaves like CallAsync, but uses CallContext internally.
+		CallAsyncContext(ctx context.Context, f BreakerFunc) <-chan error
 	}
 
 	BreakerConfigFunc func(*circuitBreaker)
 	BreakerFunc       func(ctx context.Context) error
 
 	circuitBreaker struct {
+		name                       string
 		invocationTimeout          time.Duration
 		halfClosedRetryProbability float64
+		halfClosedMaxProbes        int
 		maxConcurrency             int
 		maxConcurrencyTimeout      time.Duration
 		resetBackoff               backoff.Backoff
@@ -58,10 +72,13 @@ type (
 		tripCondition              TripCondition
 		collector                  MetricCollector
 		clock                      glock.Clock
+		sem                        chan struct{}
+		stateChangeListener        func(name string, from, to CircuitState)
 		mutex                      sync.RWMutex
 		state                      CircuitState
 		lastFailureTime            *time.Time
 		resetTimeout               *time.Duration
+		halfClosedProbes           int
 	}
 
 	CircuitState int
@@ -81,6 +98,10 @@ var (
 
 	// ErrInvocationTimeout occurs when the method takes too long to execute.
 	ErrInvocationTimeout = fmt.Errorf("invocation has timed out")
+
+	// ErrMaxConcurrency occurs when the breaker's maxConcurrency limit is
+	// reached and maxConcurrencyTimeout elapses before a slot frees up.
+	ErrMaxConcurrency = fmt.Errorf("max concurrency reached")
 )
 
 // NewCircuitBreaker creates a new CircuitBreaker.
@@ -105,7 +126,10 @@ func newCircuitBreaker(configs ...BreakerConfigFunc) *circuitBreaker {
 		config(breaker)
 	}
 
+	breaker.sem = make(chan struct{}, breaker.maxConcurrency)
+
 	breaker.collector.ReportNew(BreakerConfig{
+		Name:           breaker.name,
 		MaxConcurrency: breaker.maxConcurrency,
 	})
 
@@ -121,6 +145,15 @@ func WithHalfClosedRetryProbability(probability float64) BreakerConfigFunc {
 	return func(cb *circuitBreaker) { cb.halfClosedRetryProbability = probability }
 }
 
+// WithHalfClosedMaxProbes caps the number of concurrent in-flight probes
+// admitted while the breaker is half-closed to n. Once n probes are
+// in-flight, additional callers receive ErrCircuitOpen until one of the
+// existing probes completes. This takes precedence over
+// WithHalfClosedRetryProbability when n is greater than zero.
+func WithHalfClosedMaxProbes(n int) BreakerConfigFunc {
+	return func(cb *circuitBreaker) { cb.halfClosedMaxProbes = n }
+}
+
 func WithResetBackoff(resetBackoff backoff.Backoff) BreakerConfigFunc {
 	return func(cb *circuitBreaker) { cb.resetBackoff = resetBackoff }
 }
@@ -145,6 +178,21 @@ func WithCollector(collector MetricCollector) BreakerConfigFunc {
 	return func(cb *circuitBreaker) { cb.collector = collector }
 }
 
+// WithName sets the breaker's name, reported to the MetricCollector as part
+// of BreakerConfig and passed to the state change listener registered with
+// WithStateChangeListener. It is purely descriptive and defaults to "".
+func WithName(name string) BreakerConfigFunc {
+	return func(cb *circuitBreaker) { cb.name = name }
+}
+
+// WithStateChangeListener registers a callback which is invoked after the
+// breaker transitions from one CircuitState to another. The callback is
+// invoked outside of the breaker's critical section so that it may safely
+// call back into the breaker (e.g. ShouldTry or Call) without deadlocking.
+func WithStateChangeListener(listener func(name string, from, to CircuitState)) BreakerConfigFunc {
+	return func(cb *circuitBreaker) { cb.stateChangeListener = listener }
+}
+
 func withClock(clock glock.Clock) BreakerConfigFunc {
 	return func(cb *circuitBreaker) { cb.clock = clock }
 }
@@ -166,7 +214,7 @@ func (cb *circuitBreaker) Reset() {
 	cb.setState(StateClosed)
 	cb.resetTimeout = nil
 	cb.resetBackoff.Reset()
-	cb.tripCondition.Success()
+	cb.tripCondition.Reset()
 }
 
 func (cb *circuitBreaker) ShouldTry() bool {
@@ -182,6 +230,10 @@ func (cb *circuitBreaker) ShouldTry() bool {
 		return true
 	}
 
+	if cb.state == StateHalfClosed {
+		return cb.admitHalfClosedProbe()
+	}
+
 	if cb.state == StateClosed {
 		cb.resetBackoff.Reset()
 	}
@@ -193,14 +245,41 @@ func (cb *circuitBreaker) ShouldTry() bool {
 
 	if cb.resetTimeoutElapsed() {
 		cb.setState(StateHalfClosed)
-		return rand.Float64() < cb.halfClosedRetryProbability
+		return cb.admitHalfClosedProbe()
 	}
 
 	cb.setState(StateOpen)
 	return false
 }
 
+// admitHalfClosedProbe decides whether to admit another trial invocation
+// while the breaker is half-closed. If halfClosedMaxProbes is set, at most
+// that many probes may be in flight at once; otherwise each caller is
+// admitted with halfClosedRetryProbability.
+func (cb *circuitBreaker) admitHalfClosedProbe() bool {
+	if cb.halfClosedMaxProbes > 0 {
+		if cb.halfClosedProbes >= cb.halfClosedMaxProbes {
+			return false
+		}
+
+		cb.halfClosedProbes++
+		return true
+	}
+
+	return rand.Float64() < cb.halfClosedRetryProbability
+}
+
 func (cb *circuitBreaker) MarkResult(err error) bool {
+	cb.releaseHalfClosedProbe()
+
+	if isCallerCancellation(err) {
+		// The caller gave up on its own terms; that's not a signal about the
+		// protected call's health, so it shouldn't count as a success or a
+		// failure, and it must not undo a manual Trip or wipe out the trip
+		// condition's accumulated history the way a full Reset would.
+		return true
+	}
+
 	if err != nil && (err == ErrInvocationTimeout || cb.failureInterpreter.ShouldTrip(err)) {
 		cb.mutex.Lock()
 		defer cb.mutex.Unlock()
@@ -208,6 +287,18 @@ func (cb *circuitBreaker) MarkResult(err error) bool {
 		now := cb.clock.Now()
 		cb.lastFailureTime = &now
 		cb.tripCondition.Failure()
+
+		if cb.state == StateHalfClosed {
+			// A half-open probe failed: the backend is still unhealthy, so
+			// reopen immediately rather than continuing to admit probes
+			// (whether by probability or by slot count) until the trip
+			// condition happens to untrip on its own.
+			reset := cb.resetBackoff.NextInterval()
+			cb.resetTimeout = &reset
+			cb.halfClosedProbes = 0
+			cb.setState(StateOpen)
+		}
+
 		return false
 	}
 
@@ -216,13 +307,27 @@ func (cb *circuitBreaker) MarkResult(err error) bool {
 }
 
 func (cb *circuitBreaker) Call(f BreakerFunc) error {
+	return cb.CallContext(context.Background(), f)
+}
+
+func (cb *circuitBreaker) CallContext(ctx context.Context, f BreakerFunc) error {
 	if !cb.ShouldTry() {
 		cb.collector.ReportCount(EventTypeShortCircuit)
 		return ErrCircuitOpen
 	}
 
+	select {
+	case cb.sem <- struct{}{}:
+		defer func() { <-cb.sem }()
+
+	case <-cb.clock.After(cb.maxConcurrencyTimeout):
+		cb.releaseHalfClosedProbe()
+		cb.collector.ReportCount(EventTypeBulkheadRejection)
+		return ErrMaxConcurrency
+	}
+
 	start := time.Now()
-	err := callWithTimeout(f, cb.clock, cb.invocationTimeout)
+	err := callWithTimeout(ctx, f, cb.clock, cb.invocationTimeout)
 	elapsed := time.Now().Sub(start)
 
 	cb.collector.ReportDuration(EventTypeRunDuration, elapsed)
@@ -241,8 +346,12 @@ func (cb *circuitBreaker) Call(f BreakerFunc) error {
 }
 
 func (cb *circuitBreaker) CallAsync(f BreakerFunc) <-chan error {
+	return cb.CallAsyncContext(context.Background(), f)
+}
+
+func (cb *circuitBreaker) CallAsyncContext(ctx context.Context, f BreakerFunc) <-chan error {
 	return toErrChan(func() error {
-		return cb.Call(f)
+		return cb.CallContext(ctx, f)
 	})
 }
 
@@ -250,9 +359,28 @@ func (cb *circuitBreaker) CallAsync(f BreakerFunc) <-chan error {
 // Internal Methods
 
 func (cb *circuitBreaker) setState(state CircuitState) {
-	if cb.state != state {
-		cb.state = state
-		cb.collector.ReportState(state)
+	if cb.state == state {
+		return
+	}
+
+	from := cb.state
+	cb.state = state
+	cb.collector.ReportState(state)
+
+	if cb.stateChangeListener != nil {
+		// Run on its own goroutine, outside of the caller's locked section,
+		// so that a listener calling back into the breaker (ShouldTry, Call,
+		// etc.) can't deadlock against the lock setState was called under.
+		go cb.stateChangeListener(cb.name, from, state)
+	}
+}
+
+func (cb *circuitBreaker) releaseHalfClosedProbe() {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if cb.state == StateHalfClosed && cb.halfClosedProbes > 0 {
+		cb.halfClosedProbes--
 	}
 }
 
@@ -268,22 +396,38 @@ func (cb *circuitBreaker) resetTimeoutElapsed() bool {
 	return cb.clock.Now().Sub(*cb.lastFailureTime) >= *cb.resetTimeout
 }
 
-func callWithTimeout(f BreakerFunc, clock glock.Clock, timeout time.Duration) error {
-	if timeout == 0 {
-		return f(context.Background())
-	}
+// isCallerCancellation returns true if err reflects the caller's own context
+// being canceled or timing out, as opposed to a failure of the underlying
+// call. Such errors should not trip the circuit breaker.
+func isCallerCancellation(err error) bool {
+	return err == context.Canceled || err == context.DeadlineExceeded
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
+func callWithTimeout(ctx context.Context, f BreakerFunc, clock glock.Clock, timeout time.Duration) error {
+	childCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	ch := toErrChan(func() error {
-		return f(ctx)
+		return f(childCtx)
 	})
 
+	if timeout == 0 {
+		select {
+		case err := <-ch:
+			return err
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
 	select {
 	case err := <-ch:
 		return err
 
+	case <-ctx.Done():
+		return ctx.Err()
+
 	case <-clock.After(timeout):
 		return ErrInvocationTimeout
 	}
@@ -0,0 +1,177 @@
+package overcurrent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/aphistic/sweet"
+	"github.com/efritz/backoff"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+type BreakerSuite struct{}
+
+func (s *BreakerSuite) TestCallSuccess(t T) {
+	breaker := newCircuitBreaker()
+
+	err := breaker.Call(func(ctx context.Context) error {
+		return nil
+	})
+
+	Expect(err).To(BeNil())
+}
+
+func (s *BreakerSuite) TestCallTripsOnConsecutiveFailures(t T) {
+	breaker := newCircuitBreaker(
+		WithTripCondition(NewConsecutiveFailureTripCondition(2)),
+	)
+
+	errBoom := fmt.Errorf("boom")
+	failingCall := func(ctx context.Context) error { return errBoom }
+
+	Expect(breaker.Call(failingCall)).To(Equal(errBoom))
+	Expect(breaker.Call(failingCall)).To(Equal(errBoom))
+	Expect(breaker.Call(failingCall)).To(Equal(ErrCircuitOpen))
+}
+
+func (s *BreakerSuite) TestReset(t T) {
+	breaker := newCircuitBreaker(
+		WithTripCondition(NewConsecutiveFailureTripCondition(1)),
+	)
+
+	errBoom := fmt.Errorf("boom")
+	Expect(breaker.Call(func(ctx context.Context) error { return errBoom })).To(Equal(errBoom))
+	Expect(breaker.Call(func(ctx context.Context) error { return nil })).To(Equal(ErrCircuitOpen))
+
+	breaker.Reset()
+	Expect(breaker.Call(func(ctx context.Context) error { return nil })).To(BeNil())
+}
+
+func (s *BreakerSuite) TestMaxConcurrency(t T) {
+	breaker := newCircuitBreaker(
+		WithMaxConcurrency(1),
+		WithMaxConcurrencyTimeout(10*time.Millisecond),
+	)
+
+	block := make(chan struct{})
+
+	ch := breaker.CallAsync(func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+
+	Eventually(func() error {
+		return breaker.Call(func(ctx context.Context) error { return nil })
+	}).Should(Equal(ErrMaxConcurrency))
+
+	close(block)
+	Eventually(ch).Should(BeClosed())
+}
+
+func (s *BreakerSuite) TestStateChangeListener(t T) {
+	type transition struct {
+		name     string
+		from, to CircuitState
+	}
+
+	transitions := make(chan transition, 4)
+
+	breaker := newCircuitBreaker(
+		WithName("test-breaker"),
+		WithTripCondition(NewConsecutiveFailureTripCondition(1)),
+		WithStateChangeListener(func(name string, from, to CircuitState) {
+			transitions <- transition{name, from, to}
+		}),
+	)
+
+	errBoom := fmt.Errorf("boom")
+	breaker.Call(func(ctx context.Context) error { return errBoom })
+	breaker.ShouldTry()
+
+	var constructed transition
+	Eventually(transitions).Should(Receive(&constructed)) // zero-value -> StateClosed, on construction
+
+	var tripped transition
+	Eventually(transitions).Should(Receive(&tripped))
+	Expect(tripped.name).To(Equal("test-breaker"))
+	Expect(tripped.from).To(Equal(StateClosed))
+	Expect(tripped.to).To(Equal(StateOpen))
+}
+
+func (s *BreakerSuite) TestHalfClosedMaxProbes(t T) {
+	breaker := newCircuitBreaker(
+		WithTripCondition(NewConsecutiveFailureTripCondition(1)),
+		WithHalfClosedMaxProbes(1),
+		WithResetBackoff(backoff.NewZeroBackoff()),
+	)
+
+	errBoom := fmt.Errorf("boom")
+	Expect(breaker.Call(func(ctx context.Context) error { return errBoom })).To(Equal(errBoom))
+
+	Expect(breaker.ShouldTry()).To(BeFalse()) // Closed -> Open, trip just observed
+	Expect(breaker.ShouldTry()).To(BeTrue())  // Open -> HalfClosed, first probe admitted
+	Expect(breaker.ShouldTry()).To(BeFalse()) // still HalfClosed, probe slot exhausted
+}
+
+func (s *BreakerSuite) TestHalfClosedProbeFailureReopensCircuit(t T) {
+	clock := glock.NewMockClock()
+	breaker := newCircuitBreaker(
+		WithTripCondition(NewConsecutiveFailureTripCondition(1)),
+		WithHalfClosedRetryProbability(1.0),
+		WithResetBackoff(backoff.NewConstantBackoff(time.Minute)),
+		withClock(clock),
+	)
+
+	errBoom := fmt.Errorf("boom")
+	failingCall := func(ctx context.Context) error { return errBoom }
+
+	Expect(breaker.Call(failingCall)).To(Equal(errBoom)) // Closed -> Open, trip observed
+	Expect(breaker.ShouldTry()).To(BeFalse())            // still within backoff
+
+	clock.Advance(time.Minute)
+	Expect(breaker.Call(failingCall)).To(Equal(errBoom)) // Open -> HalfClosed, probe admitted and fails -> reopens
+
+	Expect(breaker.ShouldTry()).To(BeFalse()) // back open; must not keep admitting probes
+
+	clock.Advance(time.Minute)
+	Expect(breaker.ShouldTry()).To(BeTrue()) // backoff elapsed again, HalfClosed admits another probe
+}
+
+func (s *BreakerSuite) TestHalfClosedMaxProbesFailureReopensCircuit(t T) {
+	clock := glock.NewMockClock()
+	breaker := newCircuitBreaker(
+		WithTripCondition(NewConsecutiveFailureTripCondition(1)),
+		WithHalfClosedMaxProbes(1),
+		WithResetBackoff(backoff.NewConstantBackoff(time.Minute)),
+		withClock(clock),
+	)
+
+	errBoom := fmt.Errorf("boom")
+	failingCall := func(ctx context.Context) error { return errBoom }
+
+	Expect(breaker.Call(failingCall)).To(Equal(errBoom)) // Closed -> Open, trip observed
+	Expect(breaker.ShouldTry()).To(BeFalse())            // still within backoff
+
+	clock.Advance(time.Minute)
+	Expect(breaker.Call(failingCall)).To(Equal(errBoom)) // Open -> HalfClosed, probe admitted and fails -> reopens
+
+	Expect(breaker.ShouldTry()).To(BeFalse()) // back open; must not keep admitting probes via slot count
+
+	clock.Advance(time.Minute)
+	Expect(breaker.ShouldTry()).To(BeTrue()) // backoff elapsed again, HalfClosed admits another probe
+}
+
+func (s *BreakerSuite) TestCallerCancellationDoesNotUndoManualTrip(t T) {
+	breaker := newCircuitBreaker()
+	breaker.Trip()
+
+	Expect(breaker.ShouldTry()).To(BeFalse())
+
+	// Simulates a caller-initiated cancellation observed by a call that was
+	// already in flight when Trip was invoked concurrently.
+	Expect(breaker.MarkResult(context.Canceled)).To(BeTrue())
+
+	Expect(breaker.ShouldTry()).To(BeFalse())
+}
@@ -0,0 +1,72 @@
+package overcurrent
+
+import "time"
+
+type (
+	// EventType identifies the kind of event being reported to a MetricCollector.
+	EventType int
+
+	// BreakerConfig describes the static configuration of a circuit breaker at
+	// construction time, reported once to the collector via ReportNew.
+	BreakerConfig struct {
+		Name           string
+		MaxConcurrency int
+	}
+
+	// MetricCollector receives events emitted by a circuit breaker over its
+	// lifetime so that they can be forwarded to an external metrics system.
+	MetricCollector interface {
+		// ReportNew is invoked once when a circuit breaker is constructed.
+		ReportNew(config BreakerConfig)
+
+		// ReportState is invoked whenever the circuit breaker transitions
+		// into a new state.
+		ReportState(state CircuitState)
+
+		// ReportCount is invoked whenever a countable event occurs.
+		ReportCount(eventType EventType)
+
+		// ReportDuration is invoked to record the duration of an event.
+		ReportDuration(eventType EventType, duration time.Duration)
+	}
+
+	nullCollector struct{}
+)
+
+const (
+	_ EventType = iota
+
+	// EventTypeShortCircuit occurs when a call is rejected because the
+	// circuit is open.
+	EventTypeShortCircuit
+
+	// EventTypeTimeout occurs when an invocation fails to complete before
+	// its invocation timeout elapses.
+	EventTypeTimeout
+
+	// EventTypeError occurs when an invocation fails with an error that
+	// trips the circuit breaker.
+	EventTypeError
+
+	// EventTypeBadRequest occurs when an invocation fails with an error
+	// that the failure interpreter decides should not trip the breaker.
+	EventTypeBadRequest
+
+	// EventTypeRunDuration records the wall-clock duration of an
+	// invocation, successful or not.
+	EventTypeRunDuration
+
+	// EventTypeBulkheadRejection occurs when a call is rejected because the
+	// breaker's maxConcurrency limit was reached and maxConcurrencyTimeout
+	// elapsed before a slot became available.
+	EventTypeBulkheadRejection
+)
+
+// defaultCollector is used by circuit breakers which are not configured
+// with an explicit MetricCollector.
+var defaultCollector MetricCollector = &nullCollector{}
+
+func (c *nullCollector) ReportNew(config BreakerConfig)                            {}
+func (c *nullCollector) ReportState(state CircuitState)                            {}
+func (c *nullCollector) ReportCount(eventType EventType)                           {}
+func (c *nullCollector) ReportDuration(eventType EventType, duration time.Duration) {}
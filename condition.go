@@ -0,0 +1,274 @@
+package overcurrent
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/efritz/glock"
+)
+
+type (
+	// TripCondition is a decision function which determines if a circuit breaker
+	// should be tripped into an open state based on the success/failure events
+	// reported to it by the circuit breaker.
+	TripCondition interface {
+		// Success is invoked whenever the circuit breaker observes a successful
+		// invocation.
+		Success()
+
+		// Failure is invoked whenever the circuit breaker observes a failed
+		// invocation.
+		Failure()
+
+		// ShouldTrip returns true if the circuit breaker should move into (or
+		// remain in) the open state.
+		ShouldTrip() bool
+
+		// Reset clears any accumulated state, as if the condition had just
+		// been constructed.
+		Reset()
+	}
+
+	consecutiveFailureTripCondition struct {
+		mutex         sync.Mutex
+		failureCount  uint32
+		maxConsecutiveFailures uint32
+	}
+
+	rollingWindowBucket struct {
+		successes uint32
+		failures  uint32
+		startedAt time.Time
+	}
+
+	rollingWindowTripCondition struct {
+		mutex               sync.Mutex
+		clock               glock.Clock
+		window              time.Duration
+		bucketWidth         time.Duration
+		minRequests         uint32
+		errorRateThreshold  float64
+		buckets             []rollingWindowBucket
+		head                int
+		lastUpdatedAt       time.Time
+	}
+
+	ewmaTripCondition struct {
+		mutex         sync.Mutex
+		clock         glock.Clock
+		halfLife      time.Duration
+		sampleMin     int
+		threshold     float64
+		weightedSum   float64
+		weightedCount float64
+		sampleCount   int
+		lastUpdatedAt time.Time
+	}
+)
+
+// NewConsecutiveFailureTripCondition creates a TripCondition which trips once
+// it observes maxConsecutiveFailures failures in a row. A single success
+// resets the counter back to zero.
+func NewConsecutiveFailureTripCondition(maxConsecutiveFailures uint32) TripCondition {
+	return &consecutiveFailureTripCondition{
+		maxConsecutiveFailures: maxConsecutiveFailures,
+	}
+}
+
+func (c *consecutiveFailureTripCondition) Success() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.failureCount = 0
+}
+
+func (c *consecutiveFailureTripCondition) Failure() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.failureCount++
+}
+
+func (c *consecutiveFailureTripCondition) ShouldTrip() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.failureCount >= c.maxConsecutiveFailures
+}
+
+func (c *consecutiveFailureTripCondition) Reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.failureCount = 0
+}
+
+// NewRollingWindowTripCondition creates a TripCondition which trips when the
+// ratio of failures to total requests observed within the trailing window
+// exceeds errorRateThreshold. The window is subdivided into buckets (each
+// spanning window/buckets of wall-clock time) so that old data ages out
+// gradually instead of all at once. The condition never trips until at
+// least minRequests have been observed within the window.
+func NewRollingWindowTripCondition(window time.Duration, buckets int, minRequests uint32, errorRateThreshold float64) TripCondition {
+	return newRollingWindowTripCondition(window, buckets, minRequests, errorRateThreshold, glock.NewRealClock())
+}
+
+func newRollingWindowTripCondition(window time.Duration, buckets int, minRequests uint32, errorRateThreshold float64, clock glock.Clock) *rollingWindowTripCondition {
+	return &rollingWindowTripCondition{
+		clock:              clock,
+		window:             window,
+		bucketWidth:        window / time.Duration(buckets),
+		minRequests:        minRequests,
+		errorRateThreshold: errorRateThreshold,
+		buckets:            make([]rollingWindowBucket, buckets),
+		lastUpdatedAt:      clock.Now(),
+	}
+}
+
+func (c *rollingWindowTripCondition) Success() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.advance()
+	c.buckets[c.head].successes++
+}
+
+func (c *rollingWindowTripCondition) Failure() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.advance()
+	c.buckets[c.head].failures++
+}
+
+func (c *rollingWindowTripCondition) ShouldTrip() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.advance()
+
+	var successes, failures uint32
+	cutoff := c.clock.Now().Add(-c.window)
+
+	for _, bucket := range c.buckets {
+		if bucket.startedAt.IsZero() || bucket.startedAt.Before(cutoff) {
+			continue
+		}
+
+		successes += bucket.successes
+		failures += bucket.failures
+	}
+
+	total := successes + failures
+	if total < c.minRequests {
+		return false
+	}
+
+	return float64(failures)/float64(total) >= c.errorRateThreshold
+}
+
+func (c *rollingWindowTripCondition) Reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.buckets = make([]rollingWindowBucket, len(c.buckets))
+	c.head = 0
+	c.lastUpdatedAt = c.clock.Now()
+}
+
+// advance moves the head pointer forward by however many bucket widths have
+// elapsed since the last update, clearing each cell it passes over so that
+// stale counts don't linger into the next time it is reused.
+func (c *rollingWindowTripCondition) advance() {
+	now := c.clock.Now()
+	elapsed := now.Sub(c.lastUpdatedAt)
+	steps := int(elapsed / c.bucketWidth)
+
+	if steps > len(c.buckets) {
+		steps = len(c.buckets)
+	}
+
+	for i := 0; i < steps; i++ {
+		c.head = (c.head + 1) % len(c.buckets)
+		c.buckets[c.head] = rollingWindowBucket{}
+	}
+
+	if steps > 0 {
+		c.lastUpdatedAt = now
+	}
+
+	if c.buckets[c.head].startedAt.IsZero() {
+		c.buckets[c.head].startedAt = now
+	}
+}
+
+// NewEWMATripCondition creates a TripCondition which trips once an
+// exponentially weighted moving average of the failure signal (0 for a
+// success, 1 for a failure) reaches threshold. The average decays towards
+// the most recent samples over halfLife, so a burst of recent failures
+// outweighs a long quiet history without the boundary effects of a fixed
+// window. The condition never trips until at least sampleMin samples have
+// been observed.
+//
+// Both the sum of samples and the count of samples backing the average
+// decay at the same rate, so that samples arriving back-to-back (with
+// little or no elapsed time between them, as in a true burst) still each
+// contribute their full weight to the average; only samples separated by
+// a meaningful fraction of halfLife actually fade relative to one another.
+func NewEWMATripCondition(halfLife time.Duration, sampleMin int, threshold float64) TripCondition {
+	return newEWMATripCondition(halfLife, sampleMin, threshold, glock.NewRealClock())
+}
+
+func newEWMATripCondition(halfLife time.Duration, sampleMin int, threshold float64, clock glock.Clock) *ewmaTripCondition {
+	return &ewmaTripCondition{
+		clock:         clock,
+		halfLife:      halfLife,
+		sampleMin:     sampleMin,
+		threshold:     threshold,
+		lastUpdatedAt: clock.Now(),
+	}
+}
+
+func (c *ewmaTripCondition) Success() {
+	c.addSample(0)
+}
+
+func (c *ewmaTripCondition) Failure() {
+	c.addSample(1)
+}
+
+func (c *ewmaTripCondition) addSample(sample float64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.clock.Now()
+	elapsed := now.Sub(c.lastUpdatedAt)
+	decay := math.Exp(-float64(elapsed) / float64(c.halfLife))
+
+	c.weightedSum = c.weightedSum*decay + sample
+	c.weightedCount = c.weightedCount*decay + 1
+	c.sampleCount++
+	c.lastUpdatedAt = now
+}
+
+func (c *ewmaTripCondition) ShouldTrip() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.sampleCount < c.sampleMin || c.weightedCount == 0 {
+		return false
+	}
+
+	return c.weightedSum/c.weightedCount >= c.threshold
+}
+
+func (c *ewmaTripCondition) Reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.weightedSum = 0
+	c.weightedCount = 0
+	c.sampleCount = 0
+	c.lastUpdatedAt = c.clock.Now()
+}
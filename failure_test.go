@@ -0,0 +1,16 @@
+package overcurrent
+
+import (
+	"fmt"
+
+	. "github.com/aphistic/sweet"
+	. "github.com/onsi/gomega"
+)
+
+type FailureSuite struct{}
+
+func (s *FailureSuite) TestAnyErrorFailureInterpreter(t T) {
+	interpreter := NewAnyErrorFailureInterpreter()
+	Expect(interpreter.ShouldTrip(nil)).To(BeFalse())
+	Expect(interpreter.ShouldTrip(fmt.Errorf("boom"))).To(BeTrue())
+}
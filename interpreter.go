@@ -0,0 +1,24 @@
+package overcurrent
+
+type (
+	// FailureInterpreter determines whether an error returned from an
+	// invocation should count as a failure for the purposes of tripping
+	// the circuit breaker.
+	FailureInterpreter interface {
+		// ShouldTrip returns true if the given error should be treated as
+		// a breaker failure.
+		ShouldTrip(err error) bool
+	}
+
+	anyErrorFailureInterpreter struct{}
+)
+
+// NewAnyErrorFailureInterpreter creates a FailureInterpreter which treats
+// every non-nil error as a failure.
+func NewAnyErrorFailureInterpreter() FailureInterpreter {
+	return &anyErrorFailureInterpreter{}
+}
+
+func (i *anyErrorFailureInterpreter) ShouldTrip(err error) bool {
+	return err != nil
+}
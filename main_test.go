@@ -18,5 +18,6 @@ func TestMain(m *testing.M) {
 		s.AddSuite(&FailureSuite{})
 		s.AddSuite(&BreakerSuite{})
 		s.AddSuite(&TimeoutSuite{})
+		s.AddSuite(&PolicySuite{})
 	})
 }
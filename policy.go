@@ -0,0 +1,108 @@
+package overcurrent
+
+import (
+	"context"
+
+	"github.com/efritz/backoff"
+	"github.com/efritz/glock"
+)
+
+type (
+	// Policy composes resilience behaviors (circuit breaking, retry,
+	// fallback) around the invocation of a BreakerFunc.
+	Policy interface {
+		// Execute invokes f subject to the policy's behavior, returning
+		// the final error (if any) once the policy has run its course.
+		Execute(ctx context.Context, f BreakerFunc) error
+	}
+
+	retryPolicy struct {
+		inner    Policy
+		attempts int
+		backoff  backoff.Backoff
+		retryOn  func(err error) bool
+		clock    glock.Clock
+	}
+
+	fallbackPolicy struct {
+		inner    Policy
+		fallback func(ctx context.Context, err error) error
+	}
+)
+
+// NewRetryPolicy wraps inner with retry behavior: if inner.Execute fails, it
+// is invoked again up to attempts times (attempts total invocations), waiting
+// backoff.NextInterval() between each. retryOn is consulted on each failure
+// to decide whether it is worth retrying at all; if it is nil, every error
+// other than ErrCircuitOpen is retried. ErrCircuitOpen is never retried by
+// default, since retrying around an open breaker would defeat its purpose.
+// The retry loop aborts early, returning ctx.Err(), if ctx is done between
+// attempts. attempts is clamped to 1: a policy that never invoked f would
+// silently report success without having done any work.
+func NewRetryPolicy(inner Policy, attempts int, backoff backoff.Backoff, retryOn func(err error) bool) Policy {
+	return newRetryPolicy(inner, attempts, backoff, retryOn, glock.NewRealClock())
+}
+
+func newRetryPolicy(inner Policy, attempts int, backoff backoff.Backoff, retryOn func(err error) bool, clock glock.Clock) *retryPolicy {
+	if retryOn == nil {
+		retryOn = func(err error) bool { return err != ErrCircuitOpen }
+	}
+
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	return &retryPolicy{
+		inner:    inner,
+		attempts: attempts,
+		backoff:  backoff,
+		retryOn:  retryOn,
+		clock:    clock,
+	}
+}
+
+func (p *retryPolicy) Execute(ctx context.Context, f BreakerFunc) error {
+	p.backoff.Reset()
+
+	var err error
+	for i := 0; i < p.attempts; i++ {
+		if err = p.inner.Execute(ctx, f); err == nil || !p.retryOn(err) {
+			return err
+		}
+
+		if i == p.attempts-1 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-p.clock.After(p.backoff.NextInterval()):
+		}
+	}
+
+	return err
+}
+
+// NewFallbackPolicy wraps inner with fallback behavior: whenever
+// inner.Execute returns a non-nil error, fallback is invoked with that
+// error in its place and its return value becomes the policy's result.
+func NewFallbackPolicy(inner Policy, fallback func(ctx context.Context, err error) error) Policy {
+	return &fallbackPolicy{
+		inner:    inner,
+		fallback: fallback,
+	}
+}
+
+func (p *fallbackPolicy) Execute(ctx context.Context, f BreakerFunc) error {
+	if err := p.inner.Execute(ctx, f); err != nil {
+		return p.fallback(ctx, err)
+	}
+
+	return nil
+}
+
+func (cb *circuitBreaker) Execute(ctx context.Context, f BreakerFunc) error {
+	return cb.CallContext(ctx, f)
+}
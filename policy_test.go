@@ -0,0 +1,124 @@
+package overcurrent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/aphistic/sweet"
+	"github.com/efritz/backoff"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+type PolicySuite struct{}
+
+func (s *PolicySuite) TestRetryPolicy(t T) {
+	errBoom := fmt.Errorf("boom")
+	attempts := 0
+
+	policy := NewRetryPolicy(
+		newCircuitBreaker(),
+		3,
+		backoff.NewZeroBackoff(),
+		nil,
+	)
+
+	err := policy.Execute(context.Background(), func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errBoom
+		}
+		return nil
+	})
+
+	Expect(err).To(BeNil())
+	Expect(attempts).To(Equal(3))
+}
+
+func (s *PolicySuite) TestRetryPolicyDoesNotRetryCircuitOpen(t T) {
+	breaker := newCircuitBreaker(
+		WithTripCondition(NewConsecutiveFailureTripCondition(1)),
+	)
+
+	errBoom := fmt.Errorf("boom")
+	breaker.Call(func(ctx context.Context) error { return errBoom })
+
+	attempts := 0
+	policy := NewRetryPolicy(breaker, 3, backoff.NewZeroBackoff(), nil)
+
+	err := policy.Execute(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return nil
+	})
+
+	Expect(err).To(Equal(ErrCircuitOpen))
+	Expect(attempts).To(Equal(0))
+}
+
+func (s *PolicySuite) TestRetryPolicyAttemptsClampedToOne(t T) {
+	errBoom := fmt.Errorf("boom")
+	attempts := 0
+
+	policy := NewRetryPolicy(
+		newCircuitBreaker(),
+		0,
+		backoff.NewZeroBackoff(),
+		nil,
+	)
+
+	err := policy.Execute(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return errBoom
+	})
+
+	Expect(err).To(Equal(errBoom))
+	Expect(attempts).To(Equal(1))
+}
+
+func (s *PolicySuite) TestRetryPolicyWaitsOnClock(t T) {
+	errBoom := fmt.Errorf("boom")
+	attempts := 0
+	clock := glock.NewMockClock()
+
+	policy := newRetryPolicy(
+		newCircuitBreaker(),
+		2,
+		backoff.NewConstantBackoff(time.Minute),
+		nil,
+		clock,
+	)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- policy.Execute(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts < 2 {
+				return errBoom
+			}
+			return nil
+		})
+	}()
+
+	clock.BlockingAdvance(time.Minute)
+	Eventually(done).Should(Receive(BeNil()))
+	Expect(attempts).To(Equal(2))
+}
+
+func (s *PolicySuite) TestFallbackPolicy(t T) {
+	errBoom := fmt.Errorf("boom")
+
+	policy := NewFallbackPolicy(
+		newCircuitBreaker(),
+		func(ctx context.Context, err error) error {
+			Expect(err).To(Equal(errBoom))
+			return nil
+		},
+	)
+
+	err := policy.Execute(context.Background(), func(ctx context.Context) error {
+		return errBoom
+	})
+
+	Expect(err).To(BeNil())
+}
@@ -0,0 +1,135 @@
+// Package prommetrics provides a Prometheus-backed overcurrent.MetricCollector.
+package prommetrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/tkhunlertkit/overcurrent"
+)
+
+type collector struct {
+	name               string
+	shortCircuits      prometheus.Counter
+	timeouts           prometheus.Counter
+	errors             prometheus.Counter
+	badRequests        prometheus.Counter
+	bulkheadRejections prometheus.Counter
+	runDuration        prometheus.Observer
+	state              prometheus.Gauge
+}
+
+// New creates an overcurrent.MetricCollector which registers its metrics
+// with reg and labels them with the breaker's name. It reports
+// EventTypeShortCircuit, EventTypeTimeout, EventTypeError,
+// EventTypeBadRequest, and EventTypeBulkheadRejection as counters,
+// EventTypeRunDuration as a histogram (in seconds), and the current
+// CircuitState as a gauge.
+//
+// The underlying CounterVec/HistogramVec/GaugeVec are shared across all
+// breakers registered against the same reg, so New may be called once per
+// breaker sharing a registry without triggering a duplicate registration
+// error; each call's metrics are distinguished by the "name" label.
+func New(reg prometheus.Registerer, name string) overcurrent.MetricCollector {
+	counters := registerCounterVec(reg, prometheus.CounterOpts{
+		Namespace: "overcurrent",
+		Name:      "events_total",
+		Help:      "Total number of circuit breaker events, by event type.",
+	}, []string{"name", "event"})
+
+	runDuration := registerHistogramVec(reg, prometheus.HistogramOpts{
+		Namespace: "overcurrent",
+		Name:      "run_duration_seconds",
+		Help:      "Duration of circuit breaker invocations, in seconds.",
+	}, []string{"name"})
+
+	state := registerGaugeVec(reg, prometheus.GaugeOpts{
+		Namespace: "overcurrent",
+		Name:      "state",
+		Help:      "Current circuit breaker state (see overcurrent.CircuitState).",
+	}, []string{"name"})
+
+	return &collector{
+		name:               name,
+		shortCircuits:      counters.WithLabelValues(name, "short_circuit"),
+		timeouts:           counters.WithLabelValues(name, "timeout"),
+		errors:             counters.WithLabelValues(name, "error"),
+		badRequests:        counters.WithLabelValues(name, "bad_request"),
+		bulkheadRejections: counters.WithLabelValues(name, "bulkhead_rejection"),
+		runDuration:        runDuration.WithLabelValues(name),
+		state:              state.WithLabelValues(name),
+	}
+}
+
+// registerCounterVec registers a new CounterVec with reg, or, if a vec with
+// the same Desc was already registered (e.g. by an earlier New call against
+// the same reg), returns the existing one instead of failing.
+func registerCounterVec(reg prometheus.Registerer, opts prometheus.CounterOpts, labelNames []string) *prometheus.CounterVec {
+	vec := prometheus.NewCounterVec(opts, labelNames)
+
+	if err := reg.Register(vec); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector.(*prometheus.CounterVec)
+		}
+
+		panic(err)
+	}
+
+	return vec
+}
+
+func registerHistogramVec(reg prometheus.Registerer, opts prometheus.HistogramOpts, labelNames []string) *prometheus.HistogramVec {
+	vec := prometheus.NewHistogramVec(opts, labelNames)
+
+	if err := reg.Register(vec); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector.(*prometheus.HistogramVec)
+		}
+
+		panic(err)
+	}
+
+	return vec
+}
+
+func registerGaugeVec(reg prometheus.Registerer, opts prometheus.GaugeOpts, labelNames []string) *prometheus.GaugeVec {
+	vec := prometheus.NewGaugeVec(opts, labelNames)
+
+	if err := reg.Register(vec); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector.(*prometheus.GaugeVec)
+		}
+
+		panic(err)
+	}
+
+	return vec
+}
+
+func (c *collector) ReportNew(config overcurrent.BreakerConfig) {}
+
+func (c *collector) ReportState(state overcurrent.CircuitState) {
+	c.state.Set(float64(state))
+}
+
+func (c *collector) ReportCount(eventType overcurrent.EventType) {
+	switch eventType {
+	case overcurrent.EventTypeShortCircuit:
+		c.shortCircuits.Inc()
+	case overcurrent.EventTypeTimeout:
+		c.timeouts.Inc()
+	case overcurrent.EventTypeError:
+		c.errors.Inc()
+	case overcurrent.EventTypeBadRequest:
+		c.badRequests.Inc()
+	case overcurrent.EventTypeBulkheadRejection:
+		c.bulkheadRejections.Inc()
+	}
+}
+
+func (c *collector) ReportDuration(eventType overcurrent.EventType, duration time.Duration) {
+	if eventType == overcurrent.EventTypeRunDuration {
+		c.runDuration.Observe(duration.Seconds())
+	}
+}
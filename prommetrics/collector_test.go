@@ -0,0 +1,47 @@
+package prommetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/tkhunlertkit/overcurrent"
+)
+
+func TestCollector(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	collector := New(reg, "test-breaker")
+
+	collector.ReportState(overcurrent.StateOpen)
+	collector.ReportCount(overcurrent.EventTypeShortCircuit)
+	collector.ReportDuration(overcurrent.EventTypeRunDuration, 50*time.Millisecond)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error gathering metrics: %s", err)
+	}
+
+	if len(families) == 0 {
+		t.Fatalf("expected at least one metric family to be registered")
+	}
+}
+
+func TestCollectorMultipleBreakersShareRegistry(t *testing.T) {
+	reg := prometheus.NewRegistry()
+
+	a := New(reg, "breaker-a")
+	b := New(reg, "breaker-b")
+
+	a.ReportCount(overcurrent.EventTypeShortCircuit)
+	b.ReportCount(overcurrent.EventTypeBulkheadRejection)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("unexpected error gathering metrics: %s", err)
+	}
+
+	if len(families) == 0 {
+		t.Fatalf("expected at least one metric family to be registered")
+	}
+}
@@ -0,0 +1,53 @@
+package overcurrent
+
+import (
+	"context"
+	"time"
+
+	. "github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+type TimeoutSuite struct{}
+
+func (s *TimeoutSuite) TestInvocationTimeout(t T) {
+	clock := glock.NewMockClock()
+	breaker := newCircuitBreaker(
+		WithInvocationTimeout(time.Second),
+		withClock(clock),
+	)
+
+	block := make(chan struct{})
+	defer close(block)
+
+	ch := breaker.CallAsync(func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+
+	clock.BlockingAdvance(time.Second)
+	Eventually(ch).Should(Receive(Equal(ErrInvocationTimeout)))
+}
+
+func (s *TimeoutSuite) TestCallerCancellation(t T) {
+	breaker := newCircuitBreaker(
+		WithTripCondition(NewConsecutiveFailureTripCondition(1)),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	block := make(chan struct{})
+	defer close(block)
+
+	ch := breaker.CallAsyncContext(ctx, func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+
+	cancel()
+	Eventually(ch).Should(Receive(Equal(context.Canceled)))
+
+	// A caller-initiated cancellation should not count as a breaker failure.
+	Expect(breaker.Call(func(ctx context.Context) error { return nil })).To(BeNil())
+}
@@ -0,0 +1,94 @@
+package overcurrent
+
+import (
+	"time"
+
+	. "github.com/aphistic/sweet"
+	"github.com/efritz/glock"
+	. "github.com/onsi/gomega"
+)
+
+type TripSuite struct{}
+
+func (s *TripSuite) TestConsecutiveFailureTripCondition(t T) {
+	condition := NewConsecutiveFailureTripCondition(3)
+	Expect(condition.ShouldTrip()).To(BeFalse())
+
+	condition.Failure()
+	condition.Failure()
+	Expect(condition.ShouldTrip()).To(BeFalse())
+
+	condition.Failure()
+	Expect(condition.ShouldTrip()).To(BeTrue())
+
+	condition.Success()
+	Expect(condition.ShouldTrip()).To(BeFalse())
+}
+
+func (s *TripSuite) TestEWMATripCondition(t T) {
+	clock := glock.NewMockClock()
+	condition := newEWMATripCondition(time.Minute, 2, 0.5, clock)
+
+	condition.Failure()
+	Expect(condition.ShouldTrip()).To(BeFalse()) // below sampleMin
+
+	clock.Advance(time.Minute)
+	condition.Failure()
+	Expect(condition.ShouldTrip()).To(BeTrue())
+
+	condition.Reset()
+	Expect(condition.ShouldTrip()).To(BeFalse())
+}
+
+func (s *TripSuite) TestRollingWindowTripCondition(t T) {
+	clock := glock.NewMockClock()
+	condition := newRollingWindowTripCondition(time.Minute, 4, 4, 0.5, clock)
+
+	condition.Failure()
+	condition.Failure()
+	condition.Failure()
+	Expect(condition.ShouldTrip()).To(BeFalse()) // below minRequests
+
+	condition.Success()
+	Expect(condition.ShouldTrip()).To(BeTrue()) // 3/4 failures, above threshold
+
+	condition.Reset()
+	Expect(condition.ShouldTrip()).To(BeFalse())
+}
+
+func (s *TripSuite) TestRollingWindowTripConditionBucketExpiry(t T) {
+	clock := glock.NewMockClock()
+	condition := newRollingWindowTripCondition(time.Minute, 4, 4, 0.5, clock)
+
+	// Fill the window with failures, then age every bucket out by advancing
+	// past the full window; the old failures must no longer count towards
+	// the ratio, and minRequests must be re-satisfied by fresh samples.
+	condition.Failure()
+	condition.Failure()
+	condition.Failure()
+	condition.Failure()
+	Expect(condition.ShouldTrip()).To(BeTrue())
+
+	clock.Advance(time.Minute)
+	Expect(condition.ShouldTrip()).To(BeFalse()) // stale failures aged out, below minRequests
+
+	condition.Success()
+	condition.Success()
+	condition.Success()
+	condition.Success()
+	Expect(condition.ShouldTrip()).To(BeFalse())
+}
+
+func (s *TripSuite) TestEWMATripConditionBurst(t T) {
+	clock := glock.NewMockClock()
+	condition := newEWMATripCondition(time.Minute, 10, 0.5, clock)
+
+	// A genuine burst: many consecutive failures with no clock advance
+	// between them must still be visible in the average, not decay away
+	// to nothing just because elapsed time between samples is zero.
+	for i := 0; i < 50; i++ {
+		condition.Failure()
+	}
+
+	Expect(condition.ShouldTrip()).To(BeTrue())
+}
@@ -0,0 +1,17 @@
+package overcurrent
+
+// toErrChan invokes f in a new goroutine, returning a buffered channel which
+// receives the resulting error (if non-nil) and is then closed.
+func toErrChan(f func() error) <-chan error {
+	ch := make(chan error, 1)
+
+	go func() {
+		defer close(ch)
+
+		if err := f(); err != nil {
+			ch <- err
+		}
+	}()
+
+	return ch
+}